@@ -0,0 +1,229 @@
+package analyzer
+
+import (
+	"autopath/pkg/types"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// callNode 调用树节点，在CallTrace之上附加价值流与祖先链信息，
+// 用于区分"地址重复出现"与"真正净流出价值的重入环"
+type callNode struct {
+	address   string
+	selector  string
+	value     *big.Int
+	inValue   *big.Int
+	outValue  *big.Int
+	depth     int
+	parent    *callNode
+	ancestors []*callNode
+	children  []*callNode
+}
+
+// buildCallNodeTree 递归构建调用树，parent为nil表示根节点
+func buildCallNodeTree(trace *CallTrace, parent *callNode, depth int) *callNode {
+	if trace == nil {
+		return nil
+	}
+
+	node := &callNode{
+		address: strings.ToLower(trace.To),
+		value:   parseHexValue(trace.Value),
+		depth:   depth,
+		parent:  parent,
+	}
+
+	if len(trace.Input) >= 10 {
+		node.selector = trace.Input[:10]
+	}
+
+	if parent != nil {
+		node.ancestors = append(append([]*callNode{}, parent.ancestors...), parent)
+	}
+
+	for i := range trace.Calls {
+		if child := buildCallNodeTree(&trace.Calls[i], node, depth+1); child != nil {
+			node.children = append(node.children, child)
+		}
+	}
+
+	return node
+}
+
+// parseHexValue 解析trace中的十六进制value字段，解析失败时按0处理
+func parseHexValue(hexStr string) *big.Int {
+	trimmed := strings.TrimPrefix(hexStr, "0x")
+	if trimmed == "" {
+		return big.NewInt(0)
+	}
+
+	value, ok := new(big.Int).SetString(trimmed, 16)
+	if !ok {
+		return big.NewInt(0)
+	}
+
+	return value
+}
+
+// computeValueFlow 为调用树的每个节点填充inValue/outValue，二者都只统计该节点自身的调用帧：
+// inValue是进入这一帧的value（即CALL本身携带的value），outValue是从这一帧直接流出的value
+// （其直接子调用的value之和）。之所以只看本帧而不是整棵子树，是因为重入环净值是按
+// ancestor+node逐帧相加得到的（见buildReentrancyReports）——若这里按地址把子树中其他
+// 重入实例的value也汇总进来，同一笔转账会在环上的每个节点上被重复计入一次
+func computeValueFlow(node *callNode) {
+	if node == nil {
+		return
+	}
+
+	node.inValue = new(big.Int).Set(node.value)
+	node.outValue = big.NewInt(0)
+	for _, child := range node.children {
+		node.outValue.Add(node.outValue, child.value)
+	}
+
+	for _, child := range node.children {
+		computeValueFlow(child)
+	}
+}
+
+// reentrantCycle 一次检测到的重入环：某调用帧的目标地址已出现在其祖先链中
+type reentrantCycle struct {
+	victim    string
+	length    int
+	selectors []string
+	ancestor  *callNode
+	node      *callNode
+}
+
+// detectReentrantCycles 在调用树中查找重入环，每个节点只与其最近的同地址祖先配对，
+// 从而得到最紧凑的重入环形状
+func detectReentrantCycles(root *callNode) []reentrantCycle {
+	var cycles []reentrantCycle
+
+	var walk func(node *callNode)
+	walk = func(node *callNode) {
+		for i := len(node.ancestors) - 1; i >= 0; i-- {
+			ancestor := node.ancestors[i]
+			if ancestor.address == node.address {
+				cycles = append(cycles, reentrantCycle{
+					victim:    node.address,
+					length:    node.depth - ancestor.depth,
+					selectors: selectorsBetween(ancestor, node),
+					ancestor:  ancestor,
+					node:      node,
+				})
+				break
+			}
+		}
+
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return cycles
+}
+
+// selectorsBetween 收集从ancestor（不含）到node（含）路径上的函数选择器，按调用顺序排列
+func selectorsBetween(ancestor, node *callNode) []string {
+	var selectors []string
+	for n := node; n != nil && n != ancestor; n = n.parent {
+		if n.selector != "" {
+			selectors = append(selectors, n.selector)
+		}
+	}
+
+	for i, j := 0, len(selectors)-1; i < j; i, j = i+1, j-1 {
+		selectors[i], selectors[j] = selectors[j], selectors[i]
+	}
+
+	return selectors
+}
+
+// buildReentrancyReports 按受害者地址汇总重入环，得出环长度、重入次数、净流出价值与涉及的选择器
+func buildReentrancyReports(cycles []reentrantCycle) []types.ReentrancyReport {
+	type aggregate struct {
+		length     int
+		iterations int
+		selectors  map[string]bool
+		counted    map[*callNode]bool
+		net        *big.Int
+	}
+
+	aggregates := make(map[string]*aggregate)
+
+	for _, cycle := range cycles {
+		agg, ok := aggregates[cycle.victim]
+		if !ok {
+			agg = &aggregate{
+				length:    cycle.length,
+				selectors: make(map[string]bool),
+				counted:   make(map[*callNode]bool),
+				net:       big.NewInt(0),
+			}
+			aggregates[cycle.victim] = agg
+		}
+
+		agg.iterations++
+		if cycle.length < agg.length {
+			agg.length = cycle.length // 保留最紧凑的环形状
+		}
+
+		for _, selector := range cycle.selectors {
+			agg.selectors[selector] = true
+		}
+
+		for _, node := range []*callNode{cycle.ancestor, cycle.node} {
+			if agg.counted[node] {
+				continue
+			}
+			agg.counted[node] = true
+			agg.net.Add(agg.net, new(big.Int).Sub(node.outValue, node.inValue))
+		}
+	}
+
+	reports := make([]types.ReentrancyReport, 0, len(aggregates))
+	for victim, agg := range aggregates {
+		selectors := make([]string, 0, len(agg.selectors))
+		for selector := range agg.selectors {
+			selectors = append(selectors, selector)
+		}
+		sort.Strings(selectors)
+
+		reports = append(reports, types.ReentrancyReport{
+			Victim:          victim,
+			CycleLength:     agg.length,
+			CycleIterations: agg.iterations,
+			NetValueDrained: agg.net,
+			Selectors:       selectors,
+		})
+	}
+
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Victim < reports[j].Victim })
+
+	return reports
+}
+
+// analyzeReentrancy 基于调用树的价值流重构重入检测，取代旧版仅靠访问计数的粗粒度实现：
+// 只有当一个地址在其祖先链中重复出现时才算重入，深度则由检测到的最长重入环导出
+func (ta *TraceAnalyzer) analyzeReentrancy(trace *CallTrace) ([]types.ReentrancyReport, int) {
+	root := buildCallNodeTree(trace, nil, 0)
+	if root == nil {
+		return nil, 0
+	}
+
+	computeValueFlow(root)
+	cycles := detectReentrantCycles(root)
+	reports := buildReentrancyReports(cycles)
+
+	maxDepth := 0
+	for _, cycle := range cycles {
+		if cycle.length > maxDepth {
+			maxDepth = cycle.length
+		}
+	}
+
+	return reports, maxDepth
+}
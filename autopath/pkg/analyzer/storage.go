@@ -0,0 +1,148 @@
+package analyzer
+
+import (
+	"autopath/pkg/types"
+	"strings"
+)
+
+// storageWalker 在DFS遍历调用树的同时，维护每个(contract, slot)最近一次观测到的值，
+// 用于推导每次访问的valueBefore/valueAfter
+type storageWalker struct {
+	trace    *types.StorageAccessTrace
+	lastSeen map[string]map[string]string // contract -> slot -> 最近一次观测到的值
+	callIdx  int
+}
+
+// BuildStorageAccessTrace 遍历调用树的StructLogs，构建按(contract, slot)分组的有序
+// 存储访问轨迹。若RPC端点只返回了callTracer结果（没有structLogs），返回的轨迹为空且
+// HasStructLogs为false，调用方应据此降级为warning
+func BuildStorageAccessTrace(trace *CallTrace) *types.StorageAccessTrace {
+	result := &types.StorageAccessTrace{
+		Accesses: make(map[string]map[string][]types.StorageAccess),
+	}
+
+	w := &storageWalker{
+		trace:    result,
+		lastSeen: make(map[string]map[string]string),
+	}
+	w.walk(trace, "")
+
+	result.HasStructLogs = hasAnyStructLogs(trace)
+
+	return result
+}
+
+// hasAnyStructLogs 判断调用树中是否有任何节点携带了structLogs
+func hasAnyStructLogs(trace *CallTrace) bool {
+	if trace == nil {
+		return false
+	}
+	if len(trace.StructLogs) > 0 {
+		return true
+	}
+	for i := range trace.Calls {
+		if hasAnyStructLogs(&trace.Calls[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// walk 递归遍历调用树，记录每个节点自身StructLogs中的SLOAD/SSTORE访问。
+// storageOwner为当前帧实际归属的存储合约地址：DELEGATECALL不切换存储上下文，沿用调用方地址
+func (w *storageWalker) walk(trace *CallTrace, storageOwner string) {
+	if trace == nil {
+		return
+	}
+
+	owner := storageOwner
+	if trace.Type != "DELEGATECALL" || owner == "" {
+		owner = strings.ToLower(trace.To)
+	}
+
+	callIdx := w.callIdx
+	w.callIdx++
+
+	for _, log := range trace.StructLogs {
+		if log.Op != "SLOAD" && log.Op != "SSTORE" {
+			continue
+		}
+
+		slot, ok := storageSlotOperand(log)
+		if !ok {
+			continue
+		}
+
+		value := log.Storage[slot]
+		access := w.record(owner, slot, log.Op, log.Depth, callIdx, value)
+		w.append(owner, slot, access)
+	}
+
+	for i := range trace.Calls {
+		w.walk(&trace.Calls[i], owner)
+	}
+}
+
+// record 计算某次访问的valueBefore/valueAfter，并更新该(contract, slot)的最近观测值
+func (w *storageWalker) record(owner, slot, op string, depth, callIdx int, value string) types.StorageAccess {
+	if w.lastSeen[owner] == nil {
+		w.lastSeen[owner] = make(map[string]string)
+	}
+
+	before, known := w.lastSeen[owner][slot]
+	beforeUnknown := false
+	if !known {
+		// 首次观测该slot：SLOAD读到的就是访问前的真实值；但SSTORE前如果没有先行的SLOAD，
+		// structLogs里拿不到写入前链上的真实值（log.Storage只反映写入后的状态），
+		// 这里只能用写入后的值回退，并显式标记before不可信，而不是悄悄报告"无变化"
+		before = value
+		if op == "SSTORE" {
+			beforeUnknown = true
+		}
+	}
+
+	after := value
+	if op == "SLOAD" {
+		after = before // SLOAD不改变存储，访问前后值一致
+	}
+	w.lastSeen[owner][slot] = after
+
+	return types.StorageAccess{
+		Op:            op,
+		Depth:         depth,
+		CallIndex:     callIdx,
+		ValueBefore:   before,
+		ValueAfter:    after,
+		BeforeUnknown: beforeUnknown,
+	}
+}
+
+// append 把一次访问记录追加到对应(contract, slot)的有序列表
+func (w *storageWalker) append(owner, slot string, access types.StorageAccess) {
+	if w.trace.Accesses[owner] == nil {
+		w.trace.Accesses[owner] = make(map[string][]types.StorageAccess)
+	}
+	w.trace.Accesses[owner][slot] = append(w.trace.Accesses[owner][slot], access)
+}
+
+// storageSlotOperand 从StructLog的栈顶推导该SLOAD/SSTORE实际操作的slot。
+// log.Storage是该合约截至当前步骤、跨所有此前访问过的slot的累积快照，并不是本次操作
+// 单独的diff，不能靠遍历它的key来定位本次访问的slot——那样会把同一合约此前在不相关
+// 调用帧里touch过的其它slot也当成本次访问记录下来。SLOAD的操作数是栈顶（即将被弹出
+// 压栈取值的slot），SSTORE的操作数同样是栈顶（第二个栈元素是待写入的value）
+func storageSlotOperand(log StructLog) (string, bool) {
+	if len(log.Stack) == 0 {
+		return "", false
+	}
+	return normalizeSlotHex(log.Stack[len(log.Stack)-1]), true
+}
+
+// normalizeSlotHex 把栈上未做左侧补零的slot值，规整成与log.Storage的key一致的
+// 32字节（64位十六进制）小写格式，确保能命中累积存储快照里的value
+func normalizeSlotHex(stackItem string) string {
+	trimmed := strings.ToLower(strings.TrimPrefix(stackItem, "0x"))
+	if len(trimmed) < 64 {
+		trimmed = strings.Repeat("0", 64-len(trimmed)) + trimmed
+	}
+	return "0x" + trimmed
+}
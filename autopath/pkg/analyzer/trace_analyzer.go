@@ -93,6 +93,21 @@ func (ta *TraceAnalyzer) AnalyzeTransaction(ctx context.Context, txHash string)
 	return txData, nil
 }
 
+// AnalyzeCallTraceData 基于已获取的调用trace构建TransactionData，复用与AnalyzeTransaction
+// 相同的分析路径。供pkg/fuzzer等需要对变异复放结果重新分析的调用方使用
+func (ta *TraceAnalyzer) AnalyzeCallTraceData(trace *CallTrace) *types.TransactionData {
+	txData := &types.TransactionData{
+		BalanceChanges: make(map[string]*types.BalanceChange),
+		FunctionCalls:  make(map[string]int),
+		CallSequence:   []string{},
+		RawTrace:       trace,
+	}
+
+	ta.analyzeCallTrace(trace, txData)
+
+	return txData
+}
+
 // getTrace 获取交易trace
 func (ta *TraceAnalyzer) getTrace(ctx context.Context, txHash string) (*CallTrace, error) {
 	var result CallTrace
@@ -121,8 +136,13 @@ func (ta *TraceAnalyzer) analyzeCallTrace(trace *CallTrace, txData *types.Transa
 	// 分析循环迭代
 	txData.LoopIterations = ta.detectLoopIterations(trace)
 
-	// 分析重入深度
-	txData.ReentrancyDepth = ta.calculateReentrancyDepth(trace)
+	// 基于调用树价值流分析重入：ReentrancyDepth由检测到的重入环导出
+	reports, depth := ta.analyzeReentrancy(trace)
+	txData.ReentrancyReports = reports
+	txData.ReentrancyDepth = depth
+
+	// 分析存储访问轨迹（SLOAD/SSTORE），供check-effects-interactions与余额镜像不变量使用
+	txData.StorageTrace = BuildStorageAccessTrace(trace)
 
 	// 计算调用深度
 	txData.CallDepth = ta.calculateCallDepth(trace)
@@ -236,44 +256,6 @@ func (ta *TraceAnalyzer) countCalls(trace *CallTrace, callCounts map[string]int)
 	}
 }
 
-// calculateReentrancyDepth 计算重入深度
-func (ta *TraceAnalyzer) calculateReentrancyDepth(trace *CallTrace) int {
-	return ta.findMaxReentrantDepth(trace, make(map[string]int), 0)
-}
-
-// findMaxReentrantDepth 查找最大重入深度
-func (ta *TraceAnalyzer) findMaxReentrantDepth(trace *CallTrace, visited map[string]int, currentDepth int) int {
-	if trace == nil {
-		return currentDepth
-	}
-
-	address := strings.ToLower(trace.To)
-
-	// 如果之前访问过这个地址，说明发生了重入
-	if prevDepth, found := visited[address]; found {
-		currentDepth = prevDepth + 1
-	}
-
-	visited[address] = currentDepth
-	maxDepth := currentDepth
-
-	// 递归检查子调用
-	for _, call := range trace.Calls {
-		// 创建visited的副本，避免影响兄弟调用
-		visitedCopy := make(map[string]int)
-		for k, v := range visited {
-			visitedCopy[k] = v
-		}
-
-		depth := ta.findMaxReentrantDepth(&call, visitedCopy, currentDepth)
-		if depth > maxDepth {
-			maxDepth = depth
-		}
-	}
-
-	return maxDepth
-}
-
 // calculateCallDepth 计算调用深度
 func (ta *TraceAnalyzer) calculateCallDepth(trace *CallTrace) int {
 	if trace == nil {
@@ -0,0 +1,34 @@
+package fuzzer
+
+import (
+	"autopath/pkg/types"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// coverageKey 按调用顺序对(contract, function_selector, depth)三元组序列做哈希，
+// 作为覆盖反馈信号：指纹此前未出现过，说明本次变异探索到了新的调用路径
+func coverageKey(txData *types.TransactionData) string {
+	h := sha256.New()
+	for _, frame := range txData.CallStack {
+		fmt.Fprintf(h, "%s:%s:%d|", frame.To, frame.Function, frame.Depth)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordCoverage 记录一次调用路径指纹
+func (f *Fuzzer) recordCoverage(txData *types.TransactionData) {
+	f.seen[coverageKey(txData)] = true
+}
+
+// isNewCoverage 判断该调用路径指纹是否此前未出现过；若是新路径则登记并返回true。
+// Run只用它登记指纹，不据此保留输入，也不据此调整后续变异
+func (f *Fuzzer) isNewCoverage(txData *types.TransactionData) bool {
+	key := coverageKey(txData)
+	if f.seen[key] {
+		return false
+	}
+	f.seen[key] = true
+	return true
+}
@@ -0,0 +1,128 @@
+package fuzzer
+
+import (
+	"autopath/pkg/analyzer"
+	"autopath/pkg/invariants"
+	"autopath/pkg/types"
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Fuzzer 基于ABI的交易变异模糊器：以一笔已由TraceAnalyzer分析过的种子交易为起点，
+// 对其calldata和区块上下文做类型化变异，在fork的RPC端点上复放，探测不变量违规
+type Fuzzer struct {
+	client      *ethclient.Client
+	analyzer    *analyzer.TraceAnalyzer
+	contractABI abi.ABI
+	rules       invariants.Invariants
+	seen        map[string]bool // 覆盖反馈：已见过的调用路径指纹
+}
+
+// NewFuzzer 创建ABI引导的变异模糊器
+func NewFuzzer(client *ethclient.Client, ta *analyzer.TraceAnalyzer, contractABI abi.ABI, rules invariants.Invariants) *Fuzzer {
+	return &Fuzzer{
+		client:      client,
+		analyzer:    ta,
+		contractABI: contractABI,
+		rules:       rules,
+		seen:        make(map[string]bool),
+	}
+}
+
+// Run 对种子交易做ABI引导的变异复放，返回触发新违规的发现列表。
+// seedInput为种子交易的原始calldata，seed为AnalyzeTransaction产出的运行时数据
+func (f *Fuzzer) Run(ctx context.Context, seedTx string, seed *types.TransactionData, seedInput []byte) ([]types.FuzzFinding, error) {
+	f.recordCoverage(seed)
+
+	method, args, err := decodeSeedCall(f.contractABI, seedInput)
+	if err != nil {
+		return nil, fmt.Errorf("解析种子交易calldata失败: %w", err)
+	}
+
+	mutations := generateParamMutations(method, args, collectKnownAddresses(seed))
+	mutations = append(mutations, generateBlockContextMutations(seed)...)
+
+	var findings []types.FuzzFinding
+	for _, m := range mutations {
+		mutated, err := f.replay(ctx, seed, m)
+		if err != nil {
+			// 变异复放revert或RPC失败，跳过该变异而不是中断整个fuzzing过程
+			continue
+		}
+
+		// 登记本次调用路径指纹。其结果不参与变异的筛选、排序或派生：全部变异在循环前由种子
+		// 一次性生成，每个成功复放的变异都会评估不变量，无论它是否走到了新的调用路径
+		f.isNewCoverage(mutated)
+
+		violations := f.rules.Evaluate(mutated)
+		if len(violations) == 0 {
+			continue
+		}
+
+		findings = append(findings, types.FuzzFinding{
+			Mutation:   m,
+			TxHash:     seedTx,
+			Violations: violations,
+		})
+	}
+
+	return findings, nil
+}
+
+// replay 根据变异向量重建calldata或区块上下文覆盖，通过debug_traceCall在种子交易
+// 所在区块的下一区块上复放，并用TraceAnalyzer重建TransactionData
+func (f *Fuzzer) replay(ctx context.Context, seed *types.TransactionData, m types.FuzzMutation) (*types.TransactionData, error) {
+	callObj, blockOverrides, err := f.buildCallObject(seed, m)
+	if err != nil {
+		return nil, err
+	}
+
+	pinnedBlock := fmt.Sprintf("0x%x", seed.BlockNumber+1)
+
+	traceConfig := map[string]interface{}{
+		"tracer": "callTracer",
+	}
+	if blockOverrides != nil {
+		traceConfig["blockOverrides"] = blockOverrides
+	}
+
+	var raw analyzer.CallTrace
+	if err := f.client.Client().CallContext(ctx, &raw, "debug_traceCall", callObj, pinnedBlock, traceConfig); err != nil {
+		return nil, fmt.Errorf("debug_traceCall复放失败: %w", err)
+	}
+	if raw.Error != "" {
+		return nil, fmt.Errorf("变异复放revert: %s", raw.Error)
+	}
+
+	return f.analyzer.AnalyzeCallTraceData(&raw), nil
+}
+
+// buildCallObject 根据变异向量构造eth_call风格的调用对象，以及可选的区块上下文覆盖
+func (f *Fuzzer) buildCallObject(seed *types.TransactionData, m types.FuzzMutation) (map[string]interface{}, map[string]interface{}, error) {
+	callObj := map[string]interface{}{
+		"from": seed.From,
+		"to":   seed.To,
+	}
+
+	switch m.Kind {
+	case "param":
+		data, err := encodeMutatedCall(f.contractABI, m)
+		if err != nil {
+			return nil, nil, err
+		}
+		callObj["data"] = fmt.Sprintf("0x%x", data)
+		return callObj, nil, nil
+
+	case "block_context":
+		if len(seed.CallStack) > 0 {
+			callObj["data"] = seed.CallStack[0].Input // 沿用种子交易的原始calldata，只变异区块上下文
+		}
+		return callObj, m.Vector, nil
+
+	default:
+		return nil, nil, fmt.Errorf("未知的变异类型: %s", m.Kind)
+	}
+}
@@ -0,0 +1,262 @@
+package fuzzer
+
+import (
+	"autopath/pkg/types"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// argVariant 单个参数的一个候选变异值
+type argVariant struct {
+	label string
+	value interface{}
+}
+
+// decodeSeedCall 根据ABI解码种子交易calldata，返回匹配的方法与已解析的参数
+func decodeSeedCall(contractABI abi.ABI, input []byte) (*abi.Method, []interface{}, error) {
+	if len(input) < 4 {
+		return nil, nil, fmt.Errorf("calldata过短，无法解析函数选择器")
+	}
+
+	method, err := contractABI.MethodById(input[:4])
+	if err != nil {
+		return nil, nil, fmt.Errorf("未能根据选择器匹配ABI方法: %w", err)
+	}
+
+	args, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析calldata参数失败: %w", err)
+	}
+
+	return method, args, nil
+}
+
+// encodeMutatedCall 用变异后的参数重新编码calldata
+func encodeMutatedCall(contractABI abi.ABI, m types.FuzzMutation) ([]byte, error) {
+	functionName, _ := m.Vector["function"].(string)
+	mutatedArgs, _ := m.Vector["args"].([]interface{})
+
+	data, err := contractABI.Pack(functionName, mutatedArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("重新编码变异calldata失败: %w", err)
+	}
+
+	return data, nil
+}
+
+// generateParamMutations 按ABI类型对已解码参数做类型化变异，每次只变异一个参数
+func generateParamMutations(method *abi.Method, args []interface{}, knownAddresses []string) []types.FuzzMutation {
+	var mutations []types.FuzzMutation
+
+	for i, input := range method.Inputs {
+		for _, variant := range mutateArg(input, args[i], knownAddresses) {
+			mutatedArgs := append([]interface{}{}, args...)
+			mutatedArgs[i] = variant.value
+
+			mutations = append(mutations, types.FuzzMutation{
+				Kind:        "param",
+				Description: fmt.Sprintf("%s.%s -> %s", method.Name, input.Name, variant.label),
+				Vector: map[string]interface{}{
+					"function": method.Name,
+					"param":    input.Name,
+					"index":    i,
+					"variant":  variant.label,
+					"args":     mutatedArgs,
+				},
+			})
+		}
+	}
+
+	return mutations
+}
+
+// mutateArg 针对单个参数按其ABI类型生成候选变异值
+func mutateArg(input abi.Argument, original interface{}, knownAddresses []string) []argVariant {
+	switch {
+	case input.Type.T == abi.UintTy || input.Type.T == abi.IntTy:
+		return mutateUint(original, input.Type.Size)
+	case input.Type.T == abi.AddressTy:
+		return mutateAddress(original, knownAddresses)
+	case input.Type.T == abi.BytesTy || input.Type.T == abi.StringTy || input.Type.T == abi.FixedBytesTy:
+		return mutateBytesLike(original)
+	case input.Type.T == abi.BoolTy:
+		return mutateBool(original)
+	default:
+		return nil
+	}
+}
+
+// mutateUint 对uint/int参数生成边界值、增减量与2的幂变异
+func mutateUint(original interface{}, bits int) []argVariant {
+	if bits <= 0 {
+		bits = 256
+	}
+
+	orig, ok := toBigInt(original)
+	if !ok {
+		return nil
+	}
+
+	max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(bits)), big.NewInt(1))
+
+	variants := []argVariant{
+		{label: "zero", value: big.NewInt(0)},
+		{label: "max", value: max},
+		{label: "plus_one", value: new(big.Int).Add(orig, big.NewInt(1))},
+	}
+
+	if orig.Sign() > 0 {
+		variants = append(variants, argVariant{label: "minus_one", value: new(big.Int).Sub(orig, big.NewInt(1))})
+	}
+
+	for shift := 1; shift < bits && shift <= 128; shift *= 2 {
+		variants = append(variants, argVariant{
+			label: fmt.Sprintf("pow2_%d", shift),
+			value: new(big.Int).Lsh(big.NewInt(1), uint(shift)),
+		})
+	}
+
+	for i, delta := range randomDeltas(orig) {
+		plus := new(big.Int).Add(orig, delta)
+		if plus.Cmp(max) <= 0 {
+			variants = append(variants, argVariant{label: fmt.Sprintf("random_delta_%d", i), value: plus})
+		}
+
+		if minus := new(big.Int).Sub(orig, delta); minus.Sign() >= 0 {
+			variants = append(variants, argVariant{label: fmt.Sprintf("random_delta_neg_%d", i), value: minus})
+		}
+	}
+
+	return variants
+}
+
+// randomDeltaSeeds 小随机增减量变异的种子标签，用于从原始值派生确定性偏移，
+// 保证同一笔种子交易每次fuzzing都复现同一组变异而不依赖挂钟随机数
+var randomDeltaSeeds = []string{"rd0", "rd1", "rd2"}
+
+// randomDeltas 基于原始值对每个种子标签做哈希，派生出一组小的、确定性的"随机"偏移量
+func randomDeltas(orig *big.Int) []*big.Int {
+	deltas := make([]*big.Int, 0, len(randomDeltaSeeds))
+	for _, seed := range randomDeltaSeeds {
+		digest := crypto.Keccak256([]byte(orig.String() + seed))
+		offset := new(big.Int).Mod(new(big.Int).SetBytes(digest[:8]), big.NewInt(1000))
+		offset.Add(offset, big.NewInt(2)) // 避开0/1，与zero/plus_one/minus_one重复
+		deltas = append(deltas, offset)
+	}
+	return deltas
+}
+
+// toBigInt 把已解码的uint/int参数统一转换为*big.Int，覆盖go-ethereum ABI解码器
+// 对uint8~uint256/int8~int256可能返回的全部原生整数kind
+func toBigInt(v interface{}) (*big.Int, bool) {
+	if n, ok := v.(*big.Int); ok {
+		return n, true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), true
+	default:
+		return nil, false
+	}
+}
+
+// mutateAddress 将地址参数替换为trace中出现过的其他已知地址
+func mutateAddress(original interface{}, knownAddresses []string) []argVariant {
+	orig, _ := original.(common.Address)
+	var variants []argVariant
+	for _, addr := range knownAddresses {
+		if common.HexToAddress(addr) == orig {
+			continue
+		}
+		variants = append(variants, argVariant{label: "swap_" + addr, value: common.HexToAddress(addr)})
+	}
+	return variants
+}
+
+// mutateBytesLike 对bytes/string参数做长度变异：清空与截断
+func mutateBytesLike(original interface{}) []argVariant {
+	switch v := original.(type) {
+	case []byte:
+		variants := []argVariant{{label: "empty", value: []byte{}}}
+		if len(v) > 1 {
+			variants = append(variants, argVariant{label: "truncated", value: v[:len(v)/2]})
+		}
+		return variants
+	case string:
+		variants := []argVariant{{label: "empty", value: ""}}
+		if len(v) > 1 {
+			variants = append(variants, argVariant{label: "truncated", value: v[:len(v)/2]})
+		}
+		return variants
+	default:
+		return nil
+	}
+}
+
+// mutateBool 对bool参数做翻转变异
+func mutateBool(original interface{}) []argVariant {
+	b, ok := original.(bool)
+	if !ok {
+		return nil
+	}
+	return []argVariant{{label: "flipped", value: !b}}
+}
+
+// generateBlockContextMutations 生成block.number/timestamp/prevrandao的变异向量，
+// 供debug_traceCall的blockOverrides覆盖使用。字段名与取值编码必须匹配
+// go-ethereum internal/ethapi/override.BlockOverrides：该结构体没有json tag，
+// 靠字段名（大小写不敏感）匹配，且hexutil.Big/hexutil.Uint64/common.Hash都要求
+// 带引号的十六进制字符串，不能传裸JSON数字或布尔值
+func generateBlockContextMutations(seed *types.TransactionData) []types.FuzzMutation {
+	mutatedNumber := new(big.Int).SetUint64(seed.BlockNumber + 2) // 比replay已固定的下一区块再往后一个
+	mutatedTime := uint64(seed.Timestamp.Unix()) + 12             // 前移一个出块周期
+	prevRandao := common.BytesToHash(crypto.Keccak256([]byte(seed.TxHash + ":prevrandao")))
+
+	return []types.FuzzMutation{
+		{
+			Kind:        "block_context",
+			Description: "区块号在已固定的下一区块基础上再+1",
+			Vector:      map[string]interface{}{"number": hexutil.EncodeBig(mutatedNumber)},
+		},
+		{
+			Kind:        "block_context",
+			Description: "时间戳前移一个出块周期",
+			Vector:      map[string]interface{}{"time": hexutil.EncodeUint64(mutatedTime)},
+		},
+		{
+			Kind:        "block_context",
+			Description: "prevrandao重新随机化",
+			Vector:      map[string]interface{}{"prevrandao": prevRandao.Hex()},
+		},
+	}
+}
+
+// collectKnownAddresses 从trace中收集出现过的地址，供地址类型参数替换使用
+func collectKnownAddresses(seed *types.TransactionData) []string {
+	seenAddr := make(map[string]bool)
+	var addresses []string
+
+	for _, frame := range seed.CallStack {
+		for _, addr := range []string{frame.From, frame.To} {
+			lower := strings.ToLower(addr)
+			if lower == "" || seenAddr[lower] {
+				continue
+			}
+			seenAddr[lower] = true
+			addresses = append(addresses, addr)
+		}
+	}
+
+	return addresses
+}
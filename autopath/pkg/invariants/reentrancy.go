@@ -0,0 +1,62 @@
+package invariants
+
+import (
+	"autopath/pkg/types"
+	"fmt"
+	"math/big"
+)
+
+// ReentrancyValueThreshold 依据重入调用树的净流出价值（而非单纯的重入深度）评估不变量，
+// 避免单次良性回调（如WETH callback）误报，同时能识别真正抽干资金池的提款循环。
+// rule.Threshold需为*big.Int、十进制字符串或整数，表示触发违规的最小净流出价值
+func ReentrancyValueThreshold(rule InvariantRule, reports []types.ReentrancyReport) []types.ViolationDetail {
+	threshold := parseThreshold(rule.Threshold)
+	if threshold == nil {
+		return nil
+	}
+
+	var violations []types.ViolationDetail
+	for _, report := range reports {
+		if report.NetValueDrained == nil || report.NetValueDrained.Cmp(threshold) < 0 {
+			continue
+		}
+
+		violations = append(violations, types.ViolationDetail{
+			InvariantID:   rule.ID,
+			InvariantType: rule.Type,
+			Severity:      rule.Severity,
+			Message: fmt.Sprintf("检测到针对 %s 的重入调用环，净流出价值 %s 超过阈值 %s",
+				report.Victim, report.NetValueDrained.String(), threshold.String()),
+			Violated: true,
+			Details: map[string]interface{}{
+				"victim":            report.Victim,
+				"cycle_length":      report.CycleLength,
+				"cycle_iterations":  report.CycleIterations,
+				"net_value_drained": report.NetValueDrained.String(),
+				"selectors":         report.Selectors,
+			},
+		})
+	}
+
+	return violations
+}
+
+// parseThreshold 将规则阈值转换为*big.Int，支持*big.Int、十进制字符串与常见整数类型
+func parseThreshold(threshold interface{}) *big.Int {
+	switch v := threshold.(type) {
+	case *big.Int:
+		return v
+	case string:
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil
+		}
+		return n
+	case int64:
+		return big.NewInt(v)
+	case int:
+		return big.NewInt(int64(v))
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,170 @@
+package invariants
+
+import (
+	"autopath/pkg/types"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ReadBeforeWriteReentrancy 检测经典的check-effects-interactions违规：
+// 某slot在深度D被SLOAD之后，在外层自己的SSTORE完成落地前，被更深的子调用重入同一合约
+// 并SSTORE了同一个slot。这类攻击不一定表现为大额价值流动，纯靠深度计数或净值阈值抓不到。
+// 若rule.Contract非空，只检查该合约；否则检查所有合约
+func ReadBeforeWriteReentrancy(rule InvariantRule, storageTrace *types.StorageAccessTrace) []types.ViolationDetail {
+	if storageTrace == nil || !storageTrace.HasStructLogs {
+		return []types.ViolationDetail{storageFallbackWarning(rule, "本次交易没有structLogs（仅有callTracer输出），无法评估存储级重入")}
+	}
+
+	var violations []types.ViolationDetail
+
+	for contract, slots := range storageTrace.Accesses {
+		if rule.Contract != "" && !strings.EqualFold(contract, rule.Contract) {
+			continue
+		}
+
+		for slot, accesses := range slots {
+			for i, access := range accesses {
+				if access.Op != "SLOAD" {
+					continue
+				}
+
+				for j := i + 1; j < len(accesses); j++ {
+					next := accesses[j]
+					if next.Op != "SSTORE" {
+						continue
+					}
+
+					if next.Depth <= access.Depth {
+						break // 外层自己的写入先落地，不构成违规
+					}
+
+					violations = append(violations, types.ViolationDetail{
+						InvariantID:   rule.ID,
+						InvariantType: rule.Type,
+						Severity:      rule.Severity,
+						Message: fmt.Sprintf("合约 %s 的slot %s 在深度%d被读取后，在外层写入完成前被更深的重入调用修改",
+							contract, slot, access.Depth),
+						Violated: true,
+						Details: map[string]interface{}{
+							"contract":    contract,
+							"slot":        slot,
+							"read_depth":  access.Depth,
+							"read_call":   access.CallIndex,
+							"write_depth": next.Depth,
+							"write_call":  next.CallIndex,
+						},
+					})
+					break
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+// BalanceMirrorDivergence 比较ERC20 balanceOf映射槽位的净变化与调用树中pool实际发生的
+// 原生代币净流入/流出，超出容差则视为违规（可捕获虚假代币/记账类漏洞）。
+// rule.Contract为pool地址，rule.Metadata["slot"]为balanceOf映射槽位，rule.Threshold为容差
+func BalanceMirrorDivergence(rule InvariantRule, txData *types.TransactionData) []types.ViolationDetail {
+	pool := rule.Contract
+	slot, _ := rule.Metadata["slot"].(string)
+	if pool == "" || slot == "" {
+		return nil
+	}
+
+	if txData.StorageTrace == nil || !txData.StorageTrace.HasStructLogs {
+		return []types.ViolationDetail{storageFallbackWarning(rule, "本次交易没有structLogs（仅有callTracer输出），无法评估余额镜像divergence")}
+	}
+
+	accesses := txData.StorageTrace.Accesses[strings.ToLower(pool)][slot]
+	if len(accesses) == 0 {
+		return nil
+	}
+
+	if accesses[0].BeforeUnknown {
+		// slot在trace中的首次访问就是没有先行SLOAD的SSTORE（比如balances[to] = amount
+		// 这类直接赋值），写入前的真实链上值拿不到，此时slotDelta必然算成0，等于让这条
+		// 正是为了抓raw-write记账漏洞设计的不变量失明，所以显式降级而不是悄悄放行
+		return []types.ViolationDetail{storageFallbackWarning(rule, fmt.Sprintf(
+			"池子 %s 的slot %s 首次访问即为无先行SLOAD的SSTORE，写入前的真实值未知，无法计算slotDelta", pool, slot))}
+	}
+
+	slotDelta := hexDelta(accesses[0].ValueBefore, accesses[len(accesses)-1].ValueAfter)
+	actualDelta := netCallValue(txData, strings.ToLower(pool))
+
+	diff := new(big.Int).Sub(slotDelta, actualDelta)
+	diff.Abs(diff)
+
+	tolerance := parseThreshold(rule.Threshold)
+	if tolerance == nil {
+		tolerance = big.NewInt(0)
+	}
+
+	if diff.Cmp(tolerance) <= 0 {
+		return nil
+	}
+
+	return []types.ViolationDetail{{
+		InvariantID:   rule.ID,
+		InvariantType: rule.Type,
+		Severity:      rule.Severity,
+		Message: fmt.Sprintf("池子 %s 的balanceOf槽位净变化与实际价值流不一致，偏差 %s 超出容差 %s",
+			pool, diff.String(), tolerance.String()),
+		Violated: true,
+		Details: map[string]interface{}{
+			"pool":         pool,
+			"slot":         slot,
+			"slot_delta":   slotDelta.String(),
+			"actual_delta": actualDelta.String(),
+			"divergence":   diff.String(),
+		},
+	}}
+}
+
+// storageFallbackWarning 当RPC端点只返回callTracer、没有structLogs时的降级告警
+func storageFallbackWarning(rule InvariantRule, message string) types.ViolationDetail {
+	return types.ViolationDetail{
+		InvariantID:   rule.ID,
+		InvariantType: rule.Type,
+		Severity:      "warning",
+		Message:       message,
+		Violated:      false,
+	}
+}
+
+// hexDelta 计算两个十六进制存储值之间的差值 after - before
+func hexDelta(before, after string) *big.Int {
+	return new(big.Int).Sub(parseStorageHex(after), parseStorageHex(before))
+}
+
+// parseStorageHex 解析十六进制存储值，解析失败时按0处理
+func parseStorageHex(v string) *big.Int {
+	trimmed := strings.TrimPrefix(v, "0x")
+	if trimmed == "" {
+		return big.NewInt(0)
+	}
+
+	n, ok := new(big.Int).SetString(trimmed, 16)
+	if !ok {
+		return big.NewInt(0)
+	}
+	return n
+}
+
+// netCallValue 汇总调用树中pool作为接收方与发送方的原生代币净值
+func netCallValue(txData *types.TransactionData, pool string) *big.Int {
+	net := big.NewInt(0)
+	for _, frame := range txData.CallStack {
+		value := parseStorageHex(frame.Value)
+
+		if strings.ToLower(frame.To) == pool {
+			net.Add(net, value)
+		}
+		if strings.ToLower(frame.From) == pool {
+			net.Sub(net, value)
+		}
+	}
+	return net
+}
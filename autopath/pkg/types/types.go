@@ -29,6 +29,12 @@ type TransactionData struct {
 	LoopIterations  int `json:"loop_iterations"`
 	ReentrancyDepth int `json:"reentrancy_depth"`
 
+	// 基于调用树价值流的重入分析（按受害者地址汇总）
+	ReentrancyReports []ReentrancyReport `json:"reentrancy_reports,omitempty"`
+
+	// 存储访问轨迹（SLOAD/SSTORE），来自StructLogs
+	StorageTrace *StorageAccessTrace `json:"storage_trace,omitempty"`
+
 	// Pool数据
 	PoolUtilization float64 `json:"pool_utilization"`
 	PoolAddress     string  `json:"pool_address,omitempty"`
@@ -37,6 +43,15 @@ type TransactionData struct {
 	RawTrace interface{} `json:"raw_trace,omitempty"`
 }
 
+// ReentrancyReport 针对单个受害者地址的重入调用树分析结果
+type ReentrancyReport struct {
+	Victim          string   `json:"victim"`            // 受害者合约地址
+	CycleLength     int      `json:"cycle_length"`      // 重入环的调用深度跨度
+	CycleIterations int      `json:"cycle_iterations"`  // 该地址被重入的次数
+	NetValueDrained *big.Int `json:"net_value_drained"`  // 净流出价值（outValue - inValue，跨所有重入实例累加）
+	Selectors       []string `json:"selectors"`         // 重入环上涉及的函数选择器
+}
+
 // BalanceChange 余额变化信息
 type BalanceChange struct {
 	Address    string   `json:"address"`
@@ -60,6 +75,30 @@ type CallFrame struct {
 	Function string `json:"function"` // 函数选择器或名称
 }
 
+// StorageAccess 单次SLOAD/SSTORE访问记录
+type StorageAccess struct {
+	Op          string `json:"op"` // SLOAD或SSTORE
+	Depth       int    `json:"depth"`
+	CallIndex   int    `json:"call_index"`  // 所属调用帧在DFS遍历中的序号
+	ValueBefore string `json:"value_before"`
+	ValueAfter  string `json:"value_after"`
+
+	// BeforeUnknown标记ValueBefore并非真实的访问前值：当该slot在trace中首次出现的访问
+	// 就是SSTORE（没有先行的SLOAD）时，structLogs里拿不到写入前链上的真实值，ValueBefore
+	// 只是回退填的写入后值，依赖它的不变量应据此降级而不是误判为"无变化"
+	BeforeUnknown bool `json:"before_unknown,omitempty"`
+}
+
+// StorageAccessTrace 按(contract, slot)分组的有序存储访问轨迹
+type StorageAccessTrace struct {
+	// Accesses[contract][slot] 为该槽位按调用顺序排列的访问记录
+	Accesses map[string]map[string][]StorageAccess `json:"accesses"`
+
+	// HasStructLogs 标记RPC端点是否返回了structLogs；仅有callTracer输出时为false，
+	// 依赖StorageAccessTrace的不变量应在此时降级为warning而非直接判定未违规
+	HasStructLogs bool `json:"has_struct_logs"`
+}
+
 // ViolationDetail 不变量违规详情
 type ViolationDetail struct {
 	InvariantID   string                 `json:"invariant_id"`
@@ -84,6 +123,23 @@ type VerificationReport struct {
 
 	// 运行时数据（新增）
 	TransactionData *TransactionData `json:"transaction_data,omitempty"`
+
+	// 基于ABI引导的变异fuzzing发现（新增）
+	FuzzFindings []FuzzFinding `json:"fuzz_findings,omitempty"`
+}
+
+// FuzzMutation 描述一次针对种子交易的变异：可以是ABI参数变异，也可以是区块上下文变异
+type FuzzMutation struct {
+	Kind        string                 `json:"kind"` // param, block_context
+	Description string                 `json:"description"`
+	Vector      map[string]interface{} `json:"vector"` // 变异向量，便于红队复现
+}
+
+// FuzzFinding 一次变异复放触发的新不变量违规
+type FuzzFinding struct {
+	Mutation   FuzzMutation      `json:"mutation"`
+	TxHash     string            `json:"tx_hash,omitempty"`
+	Violations []ViolationDetail `json:"violations"`
 }
 
 // ReportSummary 报告摘要